@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// targetConfig describes one monitored target as read from the YAML config
+// file passed on the command line.
+type targetConfig struct {
+	Name string `yaml:"name"`
+	Addr string `yaml:"addr"`
+
+	// Type selects the prober backend: "icmp" (default), "tcp", or "http".
+	Type string `yaml:"type"`
+
+	Interval   time.Duration `yaml:"interval"`
+	Timeout    time.Duration `yaml:"timeout"`
+	PacketSize int           `yaml:"packet_size"`
+	Count      int           `yaml:"count"`
+	Source     string        `yaml:"source"`
+
+	// AcceptStatus lists HTTP status codes that count as a successful
+	// probe; only used when Type is "http". Defaults to any 2xx.
+	AcceptStatus []int `yaml:"accept_status"`
+
+	// Metrics enables or disables Prometheus export for this target.
+	// Defaults to true when unset.
+	Metrics *bool `yaml:"metrics"`
+}
+
+// fileConfig is the top-level shape of the YAML config file.
+type fileConfig struct {
+	Targets []targetConfig `yaml:"targets"`
+}
+
+func readPingTargets(path string) []*pingStats {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Println("Error reading config file:", err)
+		os.Exit(1)
+	}
+
+	var cfg fileConfig
+	if err := yaml.Unmarshal(fileBytes, &cfg); err != nil {
+		fmt.Println("Error parsing config file:", err)
+		os.Exit(1)
+	}
+
+	stats := make([]*pingStats, 0, len(cfg.Targets))
+	for _, t := range cfg.Targets {
+		switch t.Type {
+		case "", "icmp", "tcp", "http":
+		default:
+			fmt.Printf("Error in config file: target %q has unknown type %q (want icmp, tcp, or http)\n", t.Name, t.Type)
+			os.Exit(1)
+		}
+
+		metricsEnabled := t.Metrics == nil || *t.Metrics
+		s := newPingStats(t.Name, t.Addr, metricsEnabled)
+		s.prober = newProber(t)
+		stats = append(stats, s)
+	}
+
+	return stats
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "targets.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadPingTargets(t *testing.T) {
+	path := writeConfig(t, `
+targets:
+  - name: router
+    addr: 192.168.1.1
+  - name: api
+    addr: example.com:443
+    type: tcp
+  - name: site
+    addr: https://example.com
+    type: http
+    metrics: false
+`)
+
+	stats := readPingTargets(path)
+	if len(stats) != 3 {
+		t.Fatalf("len(stats) = %d, want 3", len(stats))
+	}
+
+	if _, ok := stats[0].prober.(*icmpProber); !ok {
+		t.Errorf("stats[0].prober = %T, want *icmpProber", stats[0].prober)
+	}
+	if !stats[0].metricsEnabled {
+		t.Errorf("stats[0].metricsEnabled = false, want true (default)")
+	}
+
+	if _, ok := stats[1].prober.(*tcpProber); !ok {
+		t.Errorf("stats[1].prober = %T, want *tcpProber", stats[1].prober)
+	}
+
+	if _, ok := stats[2].prober.(*httpProber); !ok {
+		t.Errorf("stats[2].prober = %T, want *httpProber", stats[2].prober)
+	}
+	if stats[2].metricsEnabled {
+		t.Errorf("stats[2].metricsEnabled = true, want false (explicitly disabled)")
+	}
+}
+
+// TestReadPingTargetsRejectsUnknownType exercises readPingTargets' os.Exit(1)
+// path for an unrecognized target type by re-executing this test binary as a
+// subprocess, the standard way to test os.Exit behavior in Go.
+func TestReadPingTargetsRejectsUnknownType(t *testing.T) {
+	if os.Getenv("PINGLIST_TEST_BAD_TYPE") == "1" {
+		path := writeConfig(t, `
+targets:
+  - name: bad
+    addr: 192.168.1.1
+    type: imcp
+`)
+		readPingTargets(path)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestReadPingTargetsRejectsUnknownType")
+	cmd.Env = append(os.Environ(), "PINGLIST_TEST_BAD_TYPE=1")
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && !exitErr.Success() {
+		return
+	}
+	t.Fatalf("readPingTargets did not exit nonzero on unknown type, err: %v", err)
+}
@@ -1,21 +1,18 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
+	"flag"
 	"fmt"
 	"math"
 	"os"
 	"sort"
-	"strings"
 	"sync"
 	"time"
-    "flag"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	probing "github.com/prometheus-community/pro-bing"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/term"
 )
 
@@ -29,29 +26,68 @@ type pingStats struct {
 	rttVar    float64
 	rttStdDev time.Duration
 
+	rttP50 *durationEstimator
+	rttP95 *durationEstimator
+	rttP99 *durationEstimator
+
+	// prober performs the actual probing (ICMP, TCP, or HTTP) and feeds
+	// results back through OnPktSend/OnPktRecv.
+	prober prober
+
+	// metricsEnabled controls whether this target's samples are pushed to
+	// the Prometheus gauges/counters below; it has no effect unless
+	// --metrics-addr is also set.
+	metricsEnabled  bool
+	pktsSentCounter prometheus.Counter
+	pktsRecvCounter prometheus.Counter
+	pktLossGauge    prometheus.Gauge
+	rttAvgGauge     prometheus.Gauge
+	rttStdDevGauge  prometheus.Gauge
+
 	mtx sync.Mutex
 }
 
-func newPingStats(name, addr string) *pingStats {
-	return &pingStats{
-		Name: name,
-		Addr: addr,
+func newPingStats(name, addr string, metricsEnabled bool) *pingStats {
+	s := &pingStats{
+		Name:           name,
+		Addr:           addr,
+		rttP50:         newDurationEstimator(0.5),
+		rttP95:         newDurationEstimator(0.95),
+		rttP99:         newDurationEstimator(0.99),
+		metricsEnabled: metricsEnabled,
+	}
+	if metricsEnabled {
+		s.pktsSentCounter = metricPktsSent.WithLabelValues(name, addr)
+		s.pktsRecvCounter = metricPktsRecv.WithLabelValues(name, addr)
+		s.pktLossGauge = metricPktLoss.WithLabelValues(name, addr)
+		s.rttAvgGauge = metricRttAvg.WithLabelValues(name, addr)
+		s.rttStdDevGauge = metricRttStdDev.WithLabelValues(name, addr)
 	}
+	return s
 }
 
 func (s *pingStats) PktLoss() float64 {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
+	return s.pktLossLocked()
+}
+
+// pktLossLocked computes the packet loss percentage; callers must hold mtx.
+func (s *pingStats) pktLossLocked() float64 {
 	return float64(s.pktsSent-s.pktsRecv) / float64(s.pktsSent) * 100
 }
 
-func (s *pingStats) OnPktSend(pkt *probing.Packet) {
+func (s *pingStats) OnPktSend() {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 	s.pktsSent++
+	if s.metricsEnabled {
+		s.pktsSentCounter.Inc()
+		s.pktLossGauge.Set(s.pktLossLocked())
+	}
 }
 
-func (s *pingStats) OnPktRecv(pkt *probing.Packet) {
+func (s *pingStats) OnPktRecv(rtt time.Duration) {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
@@ -59,14 +95,23 @@ func (s *pingStats) OnPktRecv(pkt *probing.Packet) {
 	// Welford's online algorithm for std dev
 	// https://en.wikipedia.org/wiki/Algorithms_for_calculating_variance#Welford's_online_algorithm
 	if s.pktsRecv == 1 {
-		s.rttAvg = pkt.Rtt
+		s.rttAvg = rtt
 		s.rttVar = 0
 	} else {
-		delta := pkt.Rtt - s.rttAvg
+		delta := rtt - s.rttAvg
 		s.rttAvg += delta / time.Duration(s.pktsRecv)
-		s.rttVar += float64(delta) * float64(pkt.Rtt-s.rttAvg)
+		s.rttVar += float64(delta) * float64(rtt-s.rttAvg)
 		s.rttStdDev = time.Duration(math.Sqrt(s.rttVar / float64(s.pktsRecv-1)))
 	}
+	s.rttP50.Add(rtt)
+	s.rttP95.Add(rtt)
+	s.rttP99.Add(rtt)
+	if s.metricsEnabled {
+		s.pktsRecvCounter.Inc()
+		s.pktLossGauge.Set(s.pktLossLocked())
+		s.rttAvgGauge.Set(s.rttAvg.Seconds())
+		s.rttStdDevGauge.Set(s.rttStdDev.Seconds())
+	}
 }
 
 func (s *pingStats) RttAvg() time.Duration {
@@ -81,46 +126,43 @@ func (s *pingStats) RttStdDev() time.Duration {
 	return s.rttStdDev
 }
 
+// RttPercentile returns the streaming P² estimate of the p-th RTT quantile
+// (p in [0, 1]). Only the quantiles tracked by newPingStats (0.5, 0.95, 0.99)
+// are supported; any other value returns 0.
+func (s *pingStats) RttPercentile(p float64) time.Duration {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	switch p {
+	case 0.5:
+		return s.rttP50.Value()
+	case 0.95:
+		return s.rttP95.Value()
+	case 0.99:
+		return s.rttP99.Value()
+	default:
+		return 0
+	}
+}
+
 func pingsToRows(pings []*pingStats) []table.Row {
 	rows := make([]table.Row, len(pings))
 	for i, s := range pings {
 		rows[i] = table.Row{
-            s.Name,
-            s.Addr,
-            fmt.Sprintf("%.1f%%", s.PktLoss()), // Packet Loss
-            s.RttAvg().Round(100 * time.Microsecond).String(), // RTT Avg
-            s.RttStdDev().Round(100 * time.Microsecond).String(), // RTT Std Dev
-        }
+			s.Name,
+			s.Addr,
+			fmt.Sprintf("%.1f%%", s.PktLoss()), // Packet Loss
+			s.RttAvg().Round(100 * time.Microsecond).String(),            // RTT Avg
+			s.RttStdDev().Round(100 * time.Microsecond).String(),         // RTT Std Dev
+			s.RttPercentile(0.5).Round(100 * time.Microsecond).String(),  // RTT P50
+			s.RttPercentile(0.95).Round(100 * time.Microsecond).String(), // RTT P95
+			s.RttPercentile(0.99).Round(100 * time.Microsecond).String(), // RTT P99
+		}
 	}
 	return rows
 }
 
 func runPing(s *pingStats) error {
-	pinger, err := probing.NewPinger(s.Addr)
-	if err != nil {
-		return err
-	}
-	pinger.OnSend = s.OnPktSend
-	pinger.OnRecv = s.OnPktRecv
-	return pinger.Run()
-}
-
-func readPingTargets(path string) []*pingStats {
-	stats := make([]*pingStats, 0)
-
-	fileBytes, err := os.ReadFile(path)
-	if err != nil {
-		fmt.Println("Error reading config file:", err)
-		os.Exit(1)
-	}
-	scanner := bufio.NewScanner(bytes.NewReader(fileBytes))
-	for scanner.Scan() {
-		line := scanner.Text()
-		name, addr, _ := strings.Cut(line, "|")
-		stats = append(stats, newPingStats(name, addr))
-	}
-
-	return stats
+	return s.prober.Run(s.OnPktSend, s.OnPktRecv)
 }
 
 type model struct {
@@ -192,19 +234,32 @@ func newTable(cols []table.Column, rows []table.Row) table.Model {
 }
 
 func main() {
-    flag.Parse()
+	metricsAddr := flag.String("metrics-addr", "", "address to serve Prometheus metrics on (e.g. :9100); empty disables the metrics endpoint")
+	flag.Parse()
 
 	pings := readPingTargets(flag.Arg(0))
 	for _, s := range pings {
 		go runPing(s)
 	}
 
+	if *metricsAddr != "" {
+		go func() {
+			if err := serveMetrics(*metricsAddr); err != nil {
+				fmt.Println("Error serving metrics:", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	cols := []table.Column{
 		{Title: "Name", Width: 30},
 		{Title: "Address", Width: 30},
 		{Title: "Packet Loss", Width: 15},
 		{Title: "RTT Avg", Width: 15},
 		{Title: "RTT Std Dev", Width: 15},
+		{Title: "RTT P50", Width: 15},
+		{Title: "RTT P95", Width: 15},
+		{Title: "RTT P99", Width: 15},
 	}
 	rows := pingsToRows(pings)
 	m := model{newTable(cols, rows), pings}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus metrics for ping targets, labeled by name and addr. Values are
+// pushed from pingStats' OnPktSend/OnPktRecv callbacks rather than collected
+// on scrape, so they stay cheap even with many targets.
+var (
+	metricPktsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pinglist_packets_sent_total",
+		Help: "Total number of ping packets sent to a target.",
+	}, []string{"name", "addr"})
+
+	metricPktsRecv = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pinglist_packets_recv_total",
+		Help: "Total number of ping packets received back from a target.",
+	}, []string{"name", "addr"})
+
+	metricPktLoss = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pinglist_packet_loss_percent",
+		Help: "Percentage of sent packets that have not been received back.",
+	}, []string{"name", "addr"})
+
+	metricRttAvg = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pinglist_rtt_avg_seconds",
+		Help: "Average round-trip time.",
+	}, []string{"name", "addr"})
+
+	metricRttStdDev = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pinglist_rtt_stddev_seconds",
+		Help: "Standard deviation of round-trip time.",
+	}, []string{"name", "addr"})
+)
+
+// serveMetrics blocks serving a Prometheus /metrics endpoint on addr.
+func serveMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,161 @@
+package main
+
+import "time"
+
+// p2Estimator implements the P² algorithm (Jain & Chlamtac, 1985) for
+// estimating a single quantile from a stream of samples in O(1) memory,
+// without buffering the samples themselves.
+//
+// It tracks 5 markers: the min, an estimate of p/2, an estimate of p, an
+// estimate of (1+p)/2, and the max of the samples seen so far.
+type p2Estimator struct {
+	p float64
+
+	n         int        // number of samples observed so far
+	initial   []float64  // buffer used only until the first 5 samples arrive
+	q         [5]float64 // marker heights (the quantile estimates)
+	pos       [5]float64 // marker positions (as floats for arithmetic ease)
+	desired   [5]float64 // desired marker positions
+	increment [5]float64 // desired position increments per observation
+}
+
+func newP2Estimator(p float64) *p2Estimator {
+	return &p2Estimator{
+		p:       p,
+		initial: make([]float64, 0, 5),
+		increment: [5]float64{
+			0,
+			p / 2,
+			p,
+			(1 + p) / 2,
+			1,
+		},
+	}
+}
+
+// Add feeds a new observation into the estimator.
+func (e *p2Estimator) Add(x float64) {
+	e.n++
+
+	if len(e.initial) < 5 {
+		e.initial = append(e.initial, x)
+		if len(e.initial) == 5 {
+			e.seed()
+		}
+		return
+	}
+
+	e.update(x)
+}
+
+// Value returns the current quantile estimate, or 0 if no samples have been
+// observed yet.
+func (e *p2Estimator) Value() float64 {
+	if len(e.initial) == 0 {
+		return 0
+	}
+	if len(e.initial) < 5 {
+		// Not enough samples yet to run P²; fall back to sorting what we have.
+		sorted := append([]float64(nil), e.initial...)
+		for i := 1; i < len(sorted); i++ {
+			for j := i; j > 0 && sorted[j-1] > sorted[j]; j-- {
+				sorted[j-1], sorted[j] = sorted[j], sorted[j-1]
+			}
+		}
+		idx := int(e.p * float64(len(sorted)-1))
+		return sorted[idx]
+	}
+	return e.q[2]
+}
+
+// seed initializes the 5 markers from the first 5 observations, sorted.
+func (e *p2Estimator) seed() {
+	sorted := append([]float64(nil), e.initial...)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j-1] > sorted[j]; j-- {
+			sorted[j-1], sorted[j] = sorted[j], sorted[j-1]
+		}
+	}
+	for i := 0; i < 5; i++ {
+		e.q[i] = sorted[i]
+		e.pos[i] = float64(i + 1)
+	}
+	for i := 0; i < 5; i++ {
+		e.desired[i] = 1 + 4*e.increment[i]
+	}
+}
+
+// update applies one P² step for a new sample x once the markers are seeded.
+func (e *p2Estimator) update(x float64) {
+	// Find the cell k such that q[k] <= x < q[k+1], extending the outer
+	// markers if x falls outside the range observed so far.
+	k := 0
+	switch {
+	case x < e.q[0]:
+		e.q[0] = x
+		k = 0
+	case x >= e.q[4]:
+		e.q[4] = x
+		k = 3
+	default:
+		for i := 0; i < 4; i++ {
+			if x < e.q[i+1] {
+				k = i
+				break
+			}
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		e.pos[i]++
+	}
+	for i := 0; i < 5; i++ {
+		e.desired[i] += e.increment[i]
+	}
+
+	for i := 1; i <= 3; i++ {
+		d := e.desired[i] - e.pos[i]
+		if (d >= 1 && e.pos[i+1]-e.pos[i] > 1) || (d <= -1 && e.pos[i-1]-e.pos[i] < -1) {
+			sign := 1.0
+			if d < 0 {
+				sign = -1.0
+			}
+			qNew := e.parabolic(i, sign)
+			if qNew <= e.q[i-1] || qNew >= e.q[i+1] {
+				qNew = e.linear(i, sign)
+			}
+			e.q[i] = qNew
+			e.pos[i] += sign
+		}
+	}
+}
+
+func (e *p2Estimator) parabolic(i int, d float64) float64 {
+	left := (e.pos[i] - e.pos[i-1] + d) * (e.q[i+1] - e.q[i]) / (e.pos[i+1] - e.pos[i])
+	right := (e.pos[i+1] - e.pos[i] - d) * (e.q[i] - e.q[i-1]) / (e.pos[i] - e.pos[i-1])
+	return e.q[i] + d/(e.pos[i+1]-e.pos[i-1])*(left+right)
+}
+
+func (e *p2Estimator) linear(i int, d float64) float64 {
+	if d > 0 {
+		return e.q[i] + (e.q[i+1]-e.q[i])/(e.pos[i+1]-e.pos[i])
+	}
+	return e.q[i] + (e.q[i-1]-e.q[i])/(e.pos[i-1]-e.pos[i])
+}
+
+// durationEstimator wraps p2Estimator for time.Duration samples.
+type durationEstimator struct {
+	*p2Estimator
+}
+
+func newDurationEstimator(p float64) *durationEstimator {
+	return &durationEstimator{newP2Estimator(p)}
+}
+
+func (e *durationEstimator) Add(d time.Duration) {
+	e.p2Estimator.Add(float64(d))
+}
+
+func (e *durationEstimator) Value() time.Duration {
+	return time.Duration(e.p2Estimator.Value())
+}
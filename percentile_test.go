@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestP2EstimatorQuantiles(t *testing.T) {
+	const n = 10000
+	r := rand.New(rand.NewSource(42))
+	samples := make([]float64, n)
+	for i := range samples {
+		samples[i] = r.Float64() * 1000
+	}
+
+	p50 := newP2Estimator(0.5)
+	p95 := newP2Estimator(0.95)
+	p99 := newP2Estimator(0.99)
+	for _, x := range samples {
+		p50.Add(x)
+		p95.Add(x)
+		p99.Add(x)
+	}
+
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	last := float64(len(sorted) - 1)
+	want50 := sorted[int(0.50*last)]
+	want95 := sorted[int(0.95*last)]
+	want99 := sorted[int(0.99*last)]
+
+	const tolerance = 0.05 // generous relative error for a streaming estimator
+	checkClose(t, "p50", p50.Value(), want50, tolerance)
+	checkClose(t, "p95", p95.Value(), want95, tolerance)
+	checkClose(t, "p99", p99.Value(), want99, tolerance)
+}
+
+func checkClose(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+	diff := got - want
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > want*tolerance {
+		t.Errorf("%s estimate = %v, want within %.0f%% of %v", name, got, tolerance*100, want)
+	}
+}
+
+func TestP2EstimatorFewSamples(t *testing.T) {
+	e := newP2Estimator(0.5)
+	if got := e.Value(); got != 0 {
+		t.Errorf("Value() with no samples = %v, want 0", got)
+	}
+
+	e.Add(3)
+	e.Add(1)
+	e.Add(2)
+	if got, want := e.Value(), 2.0; got != want {
+		t.Errorf("Value() with 3 samples = %v, want %v", got, want)
+	}
+}
+
+func TestP2EstimatorMonotonicStream(t *testing.T) {
+	e := newP2Estimator(0.5)
+	for i := 1; i <= 1000; i++ {
+		e.Add(float64(i))
+	}
+	if got := e.Value(); got < 450 || got > 550 {
+		t.Errorf("median of 1..1000 = %v, want roughly 500", got)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+)
+
+const (
+	defaultProbeInterval = time.Second
+	defaultProbeTimeout  = 5 * time.Second
+)
+
+// prober repeatedly probes a single target until the process exits or an
+// unrecoverable error occurs, reporting each attempt through onSend/onRecv —
+// the same shape as pingStats' OnPktSend/OnPktRecv — so the table, sorting,
+// and metrics exporter stay protocol-agnostic.
+type prober interface {
+	Run(onSend func(), onRecv func(rtt time.Duration)) error
+}
+
+// newProber builds the prober described by a targetConfig.
+func newProber(t targetConfig) prober {
+	switch t.Type {
+	case "tcp":
+		return &tcpProber{
+			addr:     t.Addr,
+			interval: t.Interval,
+			timeout:  t.Timeout,
+			count:    t.Count,
+		}
+	case "http":
+		return &httpProber{
+			addr:         t.Addr,
+			interval:     t.Interval,
+			timeout:      t.Timeout,
+			count:        t.Count,
+			acceptStatus: t.AcceptStatus,
+		}
+	default:
+		return &icmpProber{
+			addr:       t.Addr,
+			interval:   t.Interval,
+			timeout:    t.Timeout,
+			packetSize: t.PacketSize,
+			count:      t.Count,
+			source:     t.Source,
+		}
+	}
+}
+
+// icmpProber sends ICMP echo requests via pro-bing.
+type icmpProber struct {
+	addr       string
+	interval   time.Duration
+	timeout    time.Duration
+	packetSize int
+	count      int
+	source     string
+}
+
+func (p *icmpProber) Run(onSend func(), onRecv func(rtt time.Duration)) error {
+	pinger, err := probing.NewPinger(p.addr)
+	if err != nil {
+		return err
+	}
+	if p.interval > 0 {
+		pinger.Interval = p.interval
+	}
+	if p.timeout > 0 {
+		pinger.Timeout = p.timeout
+	}
+	if p.packetSize > 0 {
+		pinger.Size = p.packetSize
+	}
+	if p.count > 0 {
+		pinger.Count = p.count
+	}
+	if p.source != "" {
+		pinger.Source = p.source
+	}
+	pinger.OnSend = func(pkt *probing.Packet) { onSend() }
+	pinger.OnRecv = func(pkt *probing.Packet) { onRecv(pkt.Rtt) }
+	return pinger.Run()
+}
+
+// tcpProber measures TCP dial latency to addr ("host:port") on each probe.
+type tcpProber struct {
+	addr     string
+	interval time.Duration
+	timeout  time.Duration
+	count    int
+}
+
+func (p *tcpProber) Run(onSend func(), onRecv func(rtt time.Duration)) error {
+	interval := p.interval
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+	timeout := p.timeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+
+	for i := 0; p.count <= 0 || i < p.count; i++ {
+		onSend()
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", p.addr, timeout)
+		if err == nil {
+			conn.Close()
+			onRecv(time.Since(start))
+		}
+		time.Sleep(interval)
+	}
+	return nil
+}
+
+// httpProber measures HTTP GET latency to addr, treating any status code in
+// acceptStatus (or any 2xx if acceptStatus is empty) as a successful probe.
+type httpProber struct {
+	addr         string
+	interval     time.Duration
+	timeout      time.Duration
+	count        int
+	acceptStatus []int
+}
+
+func (p *httpProber) Run(onSend func(), onRecv func(rtt time.Duration)) error {
+	interval := p.interval
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+	timeout := p.timeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+
+	for i := 0; p.count <= 0 || i < p.count; i++ {
+		onSend()
+		start := time.Now()
+		resp, err := client.Get(p.addr)
+		if err == nil {
+			resp.Body.Close()
+			if p.statusAccepted(resp.StatusCode) {
+				onRecv(time.Since(start))
+			}
+		}
+		time.Sleep(interval)
+	}
+	return nil
+}
+
+func (p *httpProber) statusAccepted(code int) bool {
+	if len(p.acceptStatus) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, s := range p.acceptStatus {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package main
+
+import "testing"
+
+func TestNewProberDispatch(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want any
+	}{
+		{"", &icmpProber{}},
+		{"icmp", &icmpProber{}},
+		{"tcp", &tcpProber{}},
+		{"http", &httpProber{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			got := newProber(targetConfig{Type: tt.typ})
+			switch tt.want.(type) {
+			case *icmpProber:
+				if _, ok := got.(*icmpProber); !ok {
+					t.Errorf("newProber(Type: %q) = %T, want *icmpProber", tt.typ, got)
+				}
+			case *tcpProber:
+				if _, ok := got.(*tcpProber); !ok {
+					t.Errorf("newProber(Type: %q) = %T, want *tcpProber", tt.typ, got)
+				}
+			case *httpProber:
+				if _, ok := got.(*httpProber); !ok {
+					t.Errorf("newProber(Type: %q) = %T, want *httpProber", tt.typ, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHttpProberStatusAccepted(t *testing.T) {
+	tests := []struct {
+		name         string
+		acceptStatus []int
+		code         int
+		want         bool
+	}{
+		{"default accepts 200", nil, 200, true},
+		{"default accepts 299", nil, 299, true},
+		{"default rejects 404", nil, 404, false},
+		{"default rejects 199", nil, 199, false},
+		{"explicit list accepts listed code", []int{301, 404}, 404, true},
+		{"explicit list rejects unlisted 2xx", []int{301, 404}, 200, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &httpProber{acceptStatus: tt.acceptStatus}
+			if got := p.statusAccepted(tt.code); got != tt.want {
+				t.Errorf("statusAccepted(%d) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}